@@ -0,0 +1,103 @@
+// Package cache provides a default filesystem-backed implementation of
+// tvdb.Cache for caching series and episode lookups between runs.
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrMiss is returned by Get when a key is not present or has expired.
+var ErrMiss = errors.New("cache: miss")
+
+// entry is the on-disk envelope wrapping a cached value.
+type entry struct {
+	StoredAt time.Time
+	TTL      time.Duration
+	Data     json.RawMessage
+}
+
+// FSCache is a filesystem-backed tvdb.Cache. Each key is stored as a single
+// JSON-encoded file under Dir.
+type FSCache struct {
+	Dir string
+}
+
+// New creates an FSCache rooted at dir, creating it if necessary.
+func New(dir string) (*FSCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &FSCache{Dir: dir}, nil
+}
+
+// Get decodes the value stored under key into v. It returns ErrMiss if the
+// key is absent or has expired.
+func (c *FSCache) Get(key string, v interface{}) error {
+	data, err := ioutil.ReadFile(c.path(key))
+
+	if err != nil {
+		return ErrMiss
+	}
+
+	stored := entry{}
+
+	if err = json.Unmarshal(data, &stored); err != nil {
+		return ErrMiss
+	}
+
+	if stored.TTL > 0 && time.Since(stored.StoredAt) > stored.TTL {
+		return ErrMiss
+	}
+
+	return json.Unmarshal(stored.Data, v)
+}
+
+// Set stores v under key for the given ttl. A ttl of zero means the entry
+// never expires on its own.
+func (c *FSCache) Set(key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+
+	if err != nil {
+		return err
+	}
+
+	stored, err := json.Marshal(entry{
+		StoredAt: time.Now(),
+		TTL:      ttl,
+		Data:     data,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path(key), stored, 0644)
+}
+
+// Invalidate removes key from the cache, e.g. once a caller otherwise learns
+// that the cached value is stale and should be re-fetched on next use.
+func (c *FSCache) Invalidate(key string) error {
+	err := os.Remove(c.path(key))
+
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// path returns the on-disk path for key, hashed so arbitrary key strings
+// are always safe filenames.
+func (c *FSCache) path(key string) string {
+	sum := sha1.Sum([]byte(key))
+
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -2,15 +2,16 @@
 package tvdb
 
 import (
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -32,14 +33,23 @@ const (
 	// URL used to get detailed series/episode information by ID.
 	GET_DETAIL_URL = HOST + "/api/%v/series/%v/all/%v.xml"
 
+	// URL used to search for series by name.
+	SEARCH_SERIES_API_URL = HOST + "/api/GetSeries.php?seriesname=%v&language=%v"
+
 	// URL used for series web searches.
+	//
+	// Deprecated: only used by the scraper behind SearchSeriesScrape.
 	SEARCH_SERIES_URL = HOST + "/?string=%v&searchseriesid=&tab=listseries&function=Search"
 
 	// URL used for series web search matching.
+	//
+	// Deprecated: only used by the scraper behind SearchSeriesScrape.
 	SEARCH_SERIES_REGEX = `(?P<before><a href="/\?tab=series&amp;id=)(?P<seriesId>\d+)(?P<after>\&amp;lid=\d*">)`
 )
 
 // Regex used for series web search matching.
+//
+// Deprecated: only used by the scraper behind SearchSeriesScrape.
 var SearchSeriesRegex = regexp.MustCompile(SEARCH_SERIES_REGEX)
 
 // Type representing pipe-separated string values.
@@ -119,6 +129,8 @@ type Series struct {
 	Poster        string   `xml:"poster"`
 	Zap2ItId      string   `xml:"zap2it_id"`
 	Seasons       map[uint64][]Episode
+	Banners       []Banner
+	Cast          []Actor
 }
 
 // SeriesList represents a list of TV shows, often used for returning search results.
@@ -144,48 +156,156 @@ func NewSeries(data []byte) (*Series, error) {
 	return &series, nil
 }
 
-// Tvdb query handler
+// Tvdb query handler.
+//
+// Deprecated: thetvdb.com/api/*.xml is the legacy, unmaintained API. New
+// code should use ClientV4, which speaks the modern JSON API at
+// api.thetvdb.com and authenticates with a JWT instead of a bare API key.
 type Tvdb struct {
 	ApiKey   string
 	Language string
+
+	// Cache, if set, is consulted before every GetSeriesById request and
+	// populated after every successful one. Search requests are not cached.
+	Cache Cache
+
+	// CacheTTL controls how long entries written to Cache stay fresh.
+	CacheTTL time.Duration
+
+	// Concurrency caps the number of goroutines SearchSeries uses to
+	// hydrate matched IDs. Defaults to defaultConcurrency.
+	Concurrency int
+
+	// ContinueOnError makes SearchSeries fetch every matched ID even after
+	// one fails, returning the first error (in match order) once all
+	// fetches have finished instead of short-circuiting immediately.
+	ContinueOnError bool
+
+	httpClient *http.Client
+	limiter    *rateLimiter
 }
 
 // Tvdb query configuration
 type TvdbConfig struct {
 	ApiKey   string
 	Language string
+
+	// Cache, if set, is consulted before every Series.GetDetail request and
+	// populated after every successful one. GetSeries, GetBanners and
+	// GetActors requests are not cached.
+	Cache Cache
+
+	// CacheTTL controls how long entries written to Cache stay fresh.
+	CacheTTL time.Duration
+
+	// HTTPClient is used for every network request. Defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+
+	// RPS caps the number of requests per second issued against TheTVDB.
+	// Defaults to defaultRPS.
+	RPS int
+
+	// Concurrency caps the number of goroutines GetDetail and SearchSeries
+	// use to hydrate series/episode data. Defaults to defaultConcurrency.
+	Concurrency int
+
+	// ContinueOnError makes GetDetail and SearchSeries fetch every item
+	// even after one fails, returning the first error (in original order)
+	// once everything has finished instead of short-circuiting immediately.
+	ContinueOnError bool
+
+	limiter *rateLimiter
+}
+
+// rateLimiterFor returns config's shared rate limiter, building one from RPS
+// on first use. Callers that fan multiple requests out under the same
+// config (e.g. SeriesList.GetDetailContext) must call this once up front
+// and reuse the resulting config for every request, or each one ends up
+// with its own independent token bucket and RPS stops being enforced.
+func (config *TvdbConfig) rateLimiterFor() *rateLimiter {
+	if config.limiter == nil {
+		config.limiter = newRateLimiter(config.RPS)
+	}
+
+	return config.limiter
 }
 
 // Tvdb with a configuration
+//
+// Deprecated: part of the legacy XML client; use NewClientV4 instead.
 func NewTvdbWithConfig(config TvdbConfig) *Tvdb {
-	return &Tvdb{config.ApiKey, config.Language}
+	httpClient := config.HTTPClient
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Tvdb{
+		ApiKey:          config.ApiKey,
+		Language:        config.Language,
+		Cache:           config.Cache,
+		CacheTTL:        config.CacheTTL,
+		Concurrency:     config.Concurrency,
+		ContinueOnError: config.ContinueOnError,
+		httpClient:      httpClient,
+		limiter:         newRateLimiter(config.RPS),
+	}
 }
 
 // Tvdb with default configuration
+//
+// Deprecated: part of the legacy XML client; use NewClientV4 instead.
 func NewTvdb() *Tvdb {
-	return NewTvdbWithConfig(TvdbConfig{API_KEY, DEFAULT_LANGUAGE})
+	return NewTvdbWithConfig(TvdbConfig{ApiKey: API_KEY, Language: DEFAULT_LANGUAGE})
 }
 
 // Get more detail for all TV shows in a list.
+//
+// Deprecated: part of the legacy XML client; use ClientV4.GetDetail instead.
 func (seriesList *SeriesList) GetDetail(config TvdbConfig) (err error) {
-	for seriesIndex := range seriesList.Series {
-		if err = seriesList.Series[seriesIndex].GetDetail(config); err != nil {
-			return
-		}
-	}
+	return seriesList.GetDetailContext(context.Background(), config)
+}
 
-	return
+// Get more detail for all TV shows in a list, using up to
+// config.Concurrency goroutines and aborting early if ctx is cancelled.
+//
+// Deprecated: part of the legacy XML client; use ClientV4.GetDetail instead.
+func (seriesList *SeriesList) GetDetailContext(ctx context.Context, config TvdbConfig) (err error) {
+	// Build the rate limiter once and share it across every worker below;
+	// otherwise each goroutine would start its own fully-loaded bucket and
+	// the effective rate would be Concurrency * RPS instead of RPS.
+	config.rateLimiterFor()
+
+	return runPool(ctx, config.Concurrency, len(seriesList.Series), func(ctx context.Context, index int) error {
+		return seriesList.Series[index].GetDetailContext(ctx, config)
+	}, config.ContinueOnError)
 }
 
 // Get more detail for a TV show, including information on it's episodes.
+//
+// Deprecated: part of the legacy XML client; use ClientV4.GetDetail instead.
 func (series *Series) GetDetail(config TvdbConfig) (err error) {
-	response, err := http.Get(fmt.Sprintf(GET_DETAIL_URL, config.ApiKey, strconv.FormatUint(series.Id, 10), config.Language))
+	return series.GetDetailContext(context.Background(), config)
+}
 
-	if err != nil {
-		return
+// Get more detail for a TV show, including information on it's episodes,
+// aborting early if ctx is cancelled.
+//
+// Deprecated: part of the legacy XML client; use ClientV4.GetDetail instead.
+func (series *Series) GetDetailContext(ctx context.Context, config TvdbConfig) (err error) {
+	if config.Cache != nil {
+		cached := Series{}
+
+		if err = config.Cache.Get(detailCacheKey(series.Id, config.Language), &cached); err == nil {
+			*series = cached
+			return
+		}
+
+		err = nil
 	}
 
-	data, err := ioutil.ReadAll(response.Body)
+	data, err := httpGet(ctx, config.HTTPClient, config.rateLimiterFor(), fmt.Sprintf(GET_DETAIL_URL, config.ApiKey, strconv.FormatUint(series.Id, 10), config.Language))
 
 	if err != nil {
 		return
@@ -209,18 +329,32 @@ func (series *Series) GetDetail(config TvdbConfig) (err error) {
 		series.Seasons[episode.SeasonNumber] = append(series.Seasons[episode.SeasonNumber], episode)
 	}
 
+	if config.Cache != nil {
+		// series.LastUpdated is now authoritative: drop the shallow cache
+		// entry if it's not as fresh, so a later GetSeriesByIdContext call
+		// doesn't keep serving it even though its own TTL hasn't expired.
+		invalidateStale(config.Cache, seriesCacheKey(series.Id, config.Language), series.LastUpdated)
+
+		config.Cache.Set(detailCacheKey(series.Id, config.Language), series, config.CacheTTL)
+	}
+
 	return
 }
 
 // Get a list of TV series by name, by performing a simple search.
-func GetSeries(name string) (seriesList SeriesList, err error) {
-	response, err := http.Get(fmt.Sprintf(GET_SERIES_URL, url.QueryEscape(name)))
-
-	if err != nil {
-		return
-	}
+//
+// Deprecated: part of the legacy XML client; use NewClientV4 and
+// ClientV4.SearchSeries instead.
+func GetSeries(name string, config TvdbConfig) (seriesList SeriesList, err error) {
+	return GetSeriesContext(context.Background(), name, config)
+}
 
-	data, err := ioutil.ReadAll(response.Body)
+// Get a list of TV series by name, aborting early if ctx is cancelled.
+//
+// Deprecated: part of the legacy XML client; use NewClientV4 and
+// ClientV4.SearchSeries instead.
+func GetSeriesContext(ctx context.Context, name string, config TvdbConfig) (seriesList SeriesList, err error) {
+	data, err := httpGet(ctx, config.HTTPClient, config.rateLimiterFor(), fmt.Sprintf(GET_SERIES_URL, url.QueryEscape(name)))
 
 	if err != nil {
 		return
@@ -232,14 +366,25 @@ func GetSeries(name string) (seriesList SeriesList, err error) {
 }
 
 // Get a TV series by ID.
+//
+// Deprecated: part of the legacy XML client; use ClientV4.GetSeriesById instead.
 func (mytvdb *Tvdb) GetSeriesById(id uint64) (series Series, err error) {
-	response, err := http.Get(fmt.Sprintf(GET_SERIES_BY_ID_URL, mytvdb.ApiKey, id, mytvdb.Language))
+	return mytvdb.GetSeriesByIdContext(context.Background(), id)
+}
 
-	if err != nil {
-		return
+// Get a TV series by ID, aborting early if ctx is cancelled.
+//
+// Deprecated: part of the legacy XML client; use ClientV4.GetSeriesById instead.
+func (mytvdb *Tvdb) GetSeriesByIdContext(ctx context.Context, id uint64) (series Series, err error) {
+	if mytvdb.Cache != nil {
+		if err = mytvdb.Cache.Get(seriesCacheKey(id, mytvdb.Language), &series); err == nil {
+			return
+		}
+
+		err = nil
 	}
 
-	data, err := ioutil.ReadAll(response.Body)
+	data, err := httpGet(ctx, mytvdb.httpClient, mytvdb.limiter, fmt.Sprintf(GET_SERIES_BY_ID_URL, mytvdb.ApiKey, id, mytvdb.Language))
 
 	if err != nil {
 		return
@@ -259,19 +404,95 @@ func (mytvdb *Tvdb) GetSeriesById(id uint64) (series Series, err error) {
 
 	series = seriesList.Series[0]
 
+	if mytvdb.Cache != nil {
+		// series.LastUpdated is now authoritative: drop the detail cache
+		// entry if it's not as fresh, so a later GetDetailContext call
+		// doesn't keep serving it even though its own TTL hasn't expired.
+		invalidateStale(mytvdb.Cache, detailCacheKey(id, mytvdb.Language), series.LastUpdated)
+
+		mytvdb.Cache.Set(seriesCacheKey(id, mytvdb.Language), series, mytvdb.CacheTTL)
+	}
+
 	return
 }
 
-// Search for TV shows by name, using the more sophisticated search on TheTVDB's homepage.
-// This is the recommended search method.
-func (mytvdb *Tvdb) SearchSeries(name string, maxResults int) (seriesList SeriesList, err error) {
-	response, err := http.Get(fmt.Sprintf(SEARCH_SERIES_URL, url.QueryEscape(name)))
+// SearchOptions configures Tvdb.SearchSeries.
+type SearchOptions struct {
+	// Detailed hydrates every result via GetSeriesById. Without it,
+	// results are the shallow records the search endpoint itself returns.
+	Detailed bool
+
+	// MaxResults caps the number of series returned. Zero means no cap.
+	MaxResults int
+
+	// Language overrides mytvdb.Language for this search; "all" searches
+	// every language TheTVDB has data for. Defaults to "all".
+	Language string
+}
+
+// Search for TV shows by name, using TheTVDB's GetSeries.php search
+// endpoint. This is the recommended search method among Tvdb's own methods.
+//
+// Deprecated: part of the legacy XML client; use ClientV4.SearchSeries instead.
+func (mytvdb *Tvdb) SearchSeries(name string, options SearchOptions) (seriesList SeriesList, err error) {
+	return mytvdb.SearchSeriesContext(context.Background(), name, options)
+}
+
+// SearchSeriesContext searches for TV shows by name, aborting early if ctx
+// is cancelled.
+//
+// Deprecated: part of the legacy XML client; use ClientV4.SearchSeries instead.
+func (mytvdb *Tvdb) SearchSeriesContext(ctx context.Context, name string, options SearchOptions) (seriesList SeriesList, err error) {
+	language := options.Language
+
+	if language == "" {
+		language = "all"
+	}
+
+	data, err := httpGet(ctx, mytvdb.httpClient, mytvdb.limiter, fmt.Sprintf(SEARCH_SERIES_API_URL, url.QueryEscape(name), url.QueryEscape(language)))
 
 	if err != nil {
 		return
 	}
 
-	buf, err := ioutil.ReadAll(response.Body)
+	if err = xml.Unmarshal(data, &seriesList); err != nil {
+		return
+	}
+
+	if options.MaxResults > 0 && len(seriesList.Series) > options.MaxResults {
+		seriesList.Series = seriesList.Series[:options.MaxResults]
+	}
+
+	if options.Detailed {
+		err = runPool(ctx, mytvdb.Concurrency, len(seriesList.Series), func(ctx context.Context, index int) error {
+			detailed, fetchErr := mytvdb.GetSeriesByIdContext(ctx, seriesList.Series[index].Id)
+
+			if fetchErr != nil {
+				return fetchErr
+			}
+
+			seriesList.Series[index] = detailed
+
+			return nil
+		}, mytvdb.ContinueOnError)
+	}
+
+	return
+}
+
+// Search for TV shows by name, by scraping TheTVDB's homepage search.
+//
+// Deprecated: use SearchSeries, which is backed by the GetSeries.php search
+// endpoint instead of a regex over HTML that breaks whenever the site's
+// markup changes.
+func (mytvdb *Tvdb) SearchSeriesScrape(name string, maxResults int) (seriesList SeriesList, err error) {
+	return mytvdb.SearchSeriesScrapeContext(context.Background(), name, maxResults)
+}
+
+// SearchSeriesScrapeContext is the Deprecated, context-aware counterpart of
+// SearchSeriesScrape.
+func (mytvdb *Tvdb) SearchSeriesScrapeContext(ctx context.Context, name string, maxResults int) (seriesList SeriesList, err error) {
+	buf, err := httpGet(ctx, mytvdb.httpClient, mytvdb.limiter, fmt.Sprintf(SEARCH_SERIES_URL, url.QueryEscape(name)))
 
 	if err != nil {
 		return
@@ -279,34 +500,58 @@ func (mytvdb *Tvdb) SearchSeries(name string, maxResults int) (seriesList Series
 
 	groups := SearchSeriesRegex.FindAllSubmatch(buf, -1)
 	doneSeriesIds := make(map[uint64]struct{})
+	seriesIds := make([]uint64, 0, len(groups))
 
 	for _, group := range groups {
-		seriesId := uint64(0)
-		series := Series{}
+		var seriesId uint64
+
 		seriesId, err = strconv.ParseUint(string(group[2]), 10, 64)
 
+		if err != nil {
+			return
+		}
+
 		if _, ok := doneSeriesIds[seriesId]; ok {
 			continue
 		}
 
-		if err != nil {
-			return
-		}
+		doneSeriesIds[seriesId] = struct{}{}
+		seriesIds = append(seriesIds, seriesId)
+	}
 
-		series, err = mytvdb.GetSeriesById(seriesId)
+	results := make([]Series, len(seriesIds))
+	found := make([]bool, len(seriesIds))
 
-		if err != nil {
+	err = runPool(ctx, mytvdb.Concurrency, len(seriesIds), func(ctx context.Context, index int) error {
+		series, fetchErr := mytvdb.GetSeriesByIdContext(ctx, seriesIds[index])
+
+		if fetchErr != nil {
 			// Some series can't be found, so we will ignore these.
-			if _, ok := err.(*xml.SyntaxError); ok {
-				err = nil
-				continue
-			} else {
-				return
+			if _, ok := fetchErr.(*xml.SyntaxError); ok {
+				return nil
 			}
+
+			return fetchErr
 		}
 
-		seriesList.Series = append(seriesList.Series, series)
-		doneSeriesIds[seriesId] = struct{}{}
+		results[index] = series
+		found[index] = true
+
+		return nil
+	}, mytvdb.ContinueOnError)
+
+	if err != nil {
+		return
+	}
+
+	// Matches are hydrated concurrently, so maxResults is applied as a
+	// post-filter here rather than stopping the fetch early.
+	for index, ok := range found {
+		if !ok {
+			continue
+		}
+
+		seriesList.Series = append(seriesList.Series, results[index])
 
 		if len(seriesList.Series) == maxResults {
 			break
@@ -0,0 +1,112 @@
+package tvdb
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// URL used to get banner artwork listings by series ID.
+	GET_BANNERS_URL = HOST + "/api/%v/series/%v/banners.xml"
+
+	// URL used to get cast listings by series ID.
+	GET_ACTORS_URL = HOST + "/api/%v/series/%v/actors.xml"
+)
+
+// Banner represents a single piece of series artwork on TheTVDB.
+type Banner struct {
+	Id            uint64 `xml:"id"`
+	BannerPath    string `xml:"BannerPath"`
+	BannerType    string `xml:"BannerType"`
+	BannerType2   string `xml:"BannerType2"`
+	Colors        string `xml:"Colors"`
+	Language      string `xml:"Language"`
+	Rating        string `xml:"Rating"`
+	RatingCount   string `xml:"RatingCount"`
+	ThumbnailPath string `xml:"ThumbnailPath"`
+	VignettePath  string `xml:"VignettePath"`
+}
+
+// URL returns the absolute URL of this banner's image.
+func (banner Banner) URL() string {
+	return HOST + "/banners/" + banner.BannerPath
+}
+
+// BannersResponse represents the response from the banners.xml endpoint.
+type BannersResponse struct {
+	Banners []Banner `xml:"Banner"`
+}
+
+// Actor represents a single cast member on TheTVDB.
+type Actor struct {
+	Id        uint64 `xml:"id"`
+	Image     string `xml:"Image"`
+	Name      string `xml:"Name"`
+	Role      string `xml:"Role"`
+	SortOrder uint64 `xml:"SortOrder"`
+}
+
+// ActorsResponse represents the response from the actors.xml endpoint.
+type ActorsResponse struct {
+	Actors []Actor `xml:"Actor"`
+}
+
+// GetBanners fetches this series' banner artwork and populates Banners.
+// ClientV4 does not yet expose artwork listings, so this remains the only
+// way to fetch them regardless of which client you otherwise use.
+func (series *Series) GetBanners(config TvdbConfig) (err error) {
+	return series.GetBannersContext(context.Background(), config)
+}
+
+// GetBannersContext fetches this series' banner artwork and populates
+// Banners, aborting early if ctx is cancelled. ClientV4 does not yet expose
+// artwork listings, so this remains the only way to fetch them regardless
+// of which client you otherwise use.
+func (series *Series) GetBannersContext(ctx context.Context, config TvdbConfig) (err error) {
+	data, err := httpGet(ctx, config.HTTPClient, config.rateLimiterFor(), fmt.Sprintf(GET_BANNERS_URL, config.ApiKey, strconv.FormatUint(series.Id, 10)))
+
+	if err != nil {
+		return
+	}
+
+	response := BannersResponse{}
+
+	if err = xml.Unmarshal(data, &response); err != nil {
+		return
+	}
+
+	series.Banners = response.Banners
+
+	return
+}
+
+// GetActors fetches this series' cast and populates Cast. ClientV4 does not
+// yet expose cast listings, so this remains the only way to fetch them
+// regardless of which client you otherwise use.
+func (series *Series) GetActors(config TvdbConfig) (err error) {
+	return series.GetActorsContext(context.Background(), config)
+}
+
+// GetActorsContext fetches this series' cast and populates Cast, aborting
+// early if ctx is cancelled. ClientV4 does not yet expose cast listings, so
+// this remains the only way to fetch them regardless of which client you
+// otherwise use.
+func (series *Series) GetActorsContext(ctx context.Context, config TvdbConfig) (err error) {
+	data, err := httpGet(ctx, config.HTTPClient, config.rateLimiterFor(), fmt.Sprintf(GET_ACTORS_URL, config.ApiKey, strconv.FormatUint(series.Id, 10)))
+
+	if err != nil {
+		return
+	}
+
+	response := ActorsResponse{}
+
+	if err = xml.Unmarshal(data, &response); err != nil {
+		return
+	}
+
+	series.Cast = response.Actors
+
+	return
+}
@@ -0,0 +1,58 @@
+package tvdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// Cache is a pluggable response cache that Tvdb consults before issuing a
+// network request for series or episode data. Implementations are expected
+// to treat a miss (not found, or expired) as an error rather than a panic,
+// so callers can fall through to the network.
+type Cache interface {
+	// Get looks up key and, on a hit, decodes the cached value into v.
+	// It returns an error on a miss or decode failure.
+	Get(key string, v interface{}) error
+
+	// Set stores v under key for the given ttl.
+	Set(key string, v interface{}, ttl time.Duration) error
+}
+
+// invalidator is implemented by Cache values that support removing a
+// specific entry, such as cache.FSCache. Implementations that don't simply
+// miss out on the cross-invalidation invalidateStale performs below.
+type invalidator interface {
+	Invalidate(key string) error
+}
+
+// invalidateStale evicts the entry under key if it was cached from a Series
+// whose LastUpdated differs from current, so a later lookup under key
+// doesn't keep serving data TheTVDB has since superseded, even though its
+// TTL hasn't expired yet. It's a no-op if cache doesn't support Invalidate,
+// key is a miss, or either LastUpdated is unknown.
+func invalidateStale(cache Cache, key, current string) {
+	inv, ok := cache.(invalidator)
+
+	if !ok || current == "" {
+		return
+	}
+
+	cached := Series{}
+
+	if err := cache.Get(key, &cached); err != nil || cached.LastUpdated == "" || cached.LastUpdated == current {
+		return
+	}
+
+	inv.Invalidate(key)
+}
+
+// seriesCacheKey builds the cache key used for a shallow series lookup.
+func seriesCacheKey(id uint64, language string) string {
+	return fmt.Sprintf("tvdb.series.%v.%v", id, language)
+}
+
+// detailCacheKey builds the cache key used for a fully detailed series
+// (including its episodes).
+func detailCacheKey(id uint64, language string) string {
+	return fmt.Sprintf("tvdb.detail.%v.%v", id, language)
+}
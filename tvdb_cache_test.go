@@ -0,0 +1,80 @@
+package tvdb
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// memCache is a minimal in-memory Cache + invalidator for exercising
+// invalidateStale without touching the filesystem.
+type memCache struct {
+	entries map[string]interface{}
+}
+
+func newMemCache() *memCache {
+	return &memCache{entries: make(map[string]interface{})}
+}
+
+func (c *memCache) Get(key string, v interface{}) error {
+	stored, ok := c.entries[key]
+
+	if !ok {
+		return errMemCacheMiss
+	}
+
+	*v.(*Series) = stored.(Series)
+
+	return nil
+}
+
+func (c *memCache) Set(key string, v interface{}, ttl time.Duration) error {
+	return nil
+}
+
+func (c *memCache) Invalidate(key string) error {
+	delete(c.entries, key)
+
+	return nil
+}
+
+var errMemCacheMiss = errors.New("memCache: miss")
+
+func TestInvalidateStaleEvictsOlderEntry(t *testing.T) {
+	cache := newMemCache()
+	cache.entries["k"] = Series{LastUpdated: "100"}
+
+	invalidateStale(cache, "k", "200")
+
+	if _, ok := cache.entries["k"]; ok {
+		t.Errorf("entry with stale LastUpdated was not evicted")
+	}
+}
+
+func TestInvalidateStaleKeepsCurrentEntry(t *testing.T) {
+	cache := newMemCache()
+	cache.entries["k"] = Series{LastUpdated: "200"}
+
+	invalidateStale(cache, "k", "200")
+
+	if _, ok := cache.entries["k"]; !ok {
+		t.Errorf("entry with current LastUpdated was evicted")
+	}
+}
+
+func TestInvalidateStaleIgnoresMiss(t *testing.T) {
+	cache := newMemCache()
+
+	invalidateStale(cache, "missing", "200")
+}
+
+func TestInvalidateStaleIgnoresUnknownCurrent(t *testing.T) {
+	cache := newMemCache()
+	cache.entries["k"] = Series{LastUpdated: "100"}
+
+	invalidateStale(cache, "k", "")
+
+	if _, ok := cache.entries["k"]; !ok {
+		t.Errorf("entry was evicted despite an unknown current LastUpdated")
+	}
+}
@@ -0,0 +1,97 @@
+package tvdb
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultConcurrency is used when TvdbConfig.Concurrency is left unset or
+// non-positive.
+const defaultConcurrency = 4
+
+// runPool calls fn(ctx, i) for every i in [0, n) using up to concurrency
+// worker goroutines. Callers write their results into index i themselves,
+// so completion order doesn't matter.
+//
+// If continueOnError is false, the first error cancels ctx for the
+// remaining in-flight calls and is returned as soon as every worker has
+// stopped. If continueOnError is true, every index runs to completion
+// regardless of earlier failures, and the first error in index order is
+// returned.
+func runPool(ctx context.Context, concurrency, n int, fn func(ctx context.Context, index int) error, continueOnError bool) error {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, n)
+	jobs := make(chan int)
+	fed := 0
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := range jobs {
+				if err := fn(ctx, index); err != nil {
+					errs[index] = err
+
+					if !continueOnError {
+						once.Do(func() {
+							firstErr = err
+							cancel()
+						})
+					}
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			break feed
+		}
+
+		select {
+		case jobs <- i:
+			fed++
+		case <-ctx.Done():
+			break feed
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if continueOnError {
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// The feed loop only stops early when ctx is (or becomes) Done, so if
+	// it left indexes unfed and nothing above already reported why, ctx's
+	// own error is the reason - surface it instead of reporting success.
+	if fed < n {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
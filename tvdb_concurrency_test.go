@@ -0,0 +1,148 @@
+package tvdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunPoolRunsEveryIndex(t *testing.T) {
+	const n = 20
+
+	var seen [n]int32
+
+	err := runPool(context.Background(), 4, n, func(ctx context.Context, index int) error {
+		atomic.AddInt32(&seen[index], 1)
+		return nil
+	}, false)
+
+	if err != nil {
+		t.Fatalf("runPool returned unexpected error: %v", err)
+	}
+
+	for index, count := range seen {
+		if count != 1 {
+			t.Errorf("index %d ran %d times, want 1", index, count)
+		}
+	}
+}
+
+func TestRunPoolRespectsConcurrency(t *testing.T) {
+	const concurrency = 3
+
+	var active, maxActive, arrivals int32
+
+	var arrived sync.WaitGroup
+	arrived.Add(concurrency)
+
+	release := make(chan struct{})
+
+	go func() {
+		arrived.Wait()
+		close(release)
+	}()
+
+	// concurrency*2 jobs over `concurrency` workers: the first batch can
+	// only all be in flight at once if every worker is actually running
+	// concurrently, which is what this blocks on below. Only the first
+	// concurrency calls block on release, so later calls don't deadlock
+	// waiting for a release that already happened.
+	err := runPool(context.Background(), concurrency, concurrency*2, func(ctx context.Context, index int) error {
+		n := atomic.AddInt32(&active, 1)
+
+		for {
+			old := atomic.LoadInt32(&maxActive)
+
+			if n <= old || atomic.CompareAndSwapInt32(&maxActive, old, n) {
+				break
+			}
+		}
+
+		if atomic.AddInt32(&arrivals, 1) <= concurrency {
+			arrived.Done()
+			<-release
+		}
+
+		atomic.AddInt32(&active, -1)
+
+		return nil
+	}, false)
+
+	if err != nil {
+		t.Fatalf("runPool returned unexpected error: %v", err)
+	}
+
+	if maxActive != concurrency {
+		t.Errorf("observed %d concurrent workers, want exactly %d", maxActive, concurrency)
+	}
+}
+
+func TestRunPoolShortCircuitsOnError(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	var ran int32
+
+	err := runPool(context.Background(), 2, 50, func(ctx context.Context, index int) error {
+		atomic.AddInt32(&ran, 1)
+
+		if index == 0 {
+			return wantErr
+		}
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	}, false)
+
+	if err != wantErr {
+		t.Fatalf("runPool returned %v, want %v", err, wantErr)
+	}
+}
+
+func TestRunPoolReturnsCtxErrWhenAlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var ran int32
+
+	err := runPool(ctx, 2, 10, func(ctx context.Context, index int) error {
+		atomic.AddInt32(&ran, 1)
+		return nil
+	}, false)
+
+	if err != context.Canceled {
+		t.Fatalf("runPool returned %v, want %v", err, context.Canceled)
+	}
+
+	if ran != 0 {
+		t.Errorf("fn ran %d times against an already-cancelled ctx, want 0", ran)
+	}
+}
+
+func TestRunPoolContinueOnErrorRunsEverything(t *testing.T) {
+	const n = 10
+
+	wantErr := errors.New("boom")
+
+	var ran int32
+
+	err := runPool(context.Background(), 2, n, func(ctx context.Context, index int) error {
+		atomic.AddInt32(&ran, 1)
+
+		if index == n-1 {
+			return wantErr
+		}
+
+		return nil
+	}, true)
+
+	if err != wantErr {
+		t.Fatalf("runPool returned %v, want %v", err, wantErr)
+	}
+
+	if int(ran) != n {
+		t.Errorf("ran %d of %d indexes, want all of them with ContinueOnError", ran, n)
+	}
+}
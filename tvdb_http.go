@@ -0,0 +1,137 @@
+package tvdb
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRPS is used when TvdbConfig.RPS is left unset or non-positive.
+	defaultRPS = 10
+
+	// maxRetries caps the number of retries, after the first attempt, made
+	// for a request that keeps failing with a 429 or 5xx response.
+	maxRetries = 3
+
+	// retryBaseDelay is the starting delay for the exponential backoff
+	// between retries.
+	retryBaseDelay = 250 * time.Millisecond
+)
+
+// rateLimiter is a small token-bucket limiter, good enough to keep this
+// module from hammering TheTVDB during bulk lookups such as
+// SeriesList.GetDetail. Tokens are refilled lazily based on elapsed time,
+// so a rateLimiter needs no background goroutine to shut down.
+type rateLimiter struct {
+	mu        sync.Mutex
+	rps       float64
+	tokens    float64
+	maxTokens float64
+	last      time.Time
+}
+
+// newRateLimiter creates a rateLimiter allowing rps requests per second. A
+// non-positive rps falls back to defaultRPS.
+func newRateLimiter(rps int) *rateLimiter {
+	if rps <= 0 {
+		rps = defaultRPS
+	}
+
+	return &rateLimiter{
+		rps:       float64(rps),
+		tokens:    float64(rps),
+		maxTokens: float64(rps),
+		last:      time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is cancelled.
+func (limiter *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		limiter.mu.Lock()
+
+		now := time.Now()
+		limiter.tokens += now.Sub(limiter.last).Seconds() * limiter.rps
+		limiter.last = now
+
+		if limiter.tokens > limiter.maxTokens {
+			limiter.tokens = limiter.maxTokens
+		}
+
+		if limiter.tokens >= 1 {
+			limiter.tokens--
+			limiter.mu.Unlock()
+
+			return nil
+		}
+
+		wait := time.Duration((1 - limiter.tokens) / limiter.rps * float64(time.Second))
+
+		limiter.mu.Unlock()
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// httpGet issues a rate-limited GET request through client, retrying with
+// exponential backoff on 429 and 5xx responses, and returns the response
+// body. A nil client falls back to http.DefaultClient; a nil limiter skips
+// rate limiting.
+func httpGet(ctx context.Context, client *http.Client, limiter *rateLimiter, url string) (data []byte, err error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	delay := retryBaseDelay
+
+	for attempt := 0; ; attempt++ {
+		if limiter != nil {
+			if err = limiter.Wait(ctx); err != nil {
+				return
+			}
+		}
+
+		var request *http.Request
+
+		if request, err = http.NewRequestWithContext(ctx, "GET", url, nil); err != nil {
+			return
+		}
+
+		var response *http.Response
+
+		if response, err = client.Do(request); err != nil {
+			return
+		}
+
+		if response.StatusCode != http.StatusTooManyRequests && response.StatusCode < http.StatusInternalServerError {
+			data, err = ioutil.ReadAll(response.Body)
+			response.Body.Close()
+
+			return
+		}
+
+		response.Body.Close()
+
+		if attempt == maxRetries {
+			err = fmt.Errorf("tvdb: request to %v failed with status %v", url, response.StatusCode)
+			return
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+
+		delay *= 2
+	}
+}
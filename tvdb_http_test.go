@@ -0,0 +1,69 @@
+package tvdb
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterEnforcesRPS(t *testing.T) {
+	const rps = 5
+
+	limiter := newRateLimiter(rps)
+	ctx := context.Background()
+
+	// Drain the initial full bucket.
+	for i := 0; i < rps; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait() returned unexpected error: %v", err)
+		}
+	}
+
+	start := time.Now()
+
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("Wait() returned unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Wait() returned after %v once the bucket was empty, want to block roughly 1/%d s", elapsed, rps)
+	}
+}
+
+func TestRateLimiterSharedAcrossCallersEnforcesAggregateRate(t *testing.T) {
+	const rps = 5
+
+	limiter := newRateLimiter(rps)
+	ctx := context.Background()
+
+	// Two independent callers sharing the same limiter should not be able
+	// to together exceed rps requests per second; this is the scenario
+	// that a freshly-built-per-call limiter would fail to protect against.
+	drain := func() {
+		for i := 0; i < rps; i++ {
+			if err := limiter.Wait(ctx); err != nil {
+				t.Fatalf("Wait() returned unexpected error: %v", err)
+			}
+		}
+	}
+
+	drain()
+
+	start := time.Now()
+
+	done := make(chan struct{}, 2)
+
+	for i := 0; i < 2; i++ {
+		go func() {
+			limiter.Wait(ctx)
+			done <- struct{}{}
+		}()
+	}
+
+	<-done
+	<-done
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("two callers drained an already-empty shared bucket after %v, want to block roughly 1/%d s", elapsed, rps)
+	}
+}
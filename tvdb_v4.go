@@ -0,0 +1,304 @@
+package tvdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// Protocol and host prefix for the modern JSON API.
+	HOST_V4 = "https://api.thetvdb.com"
+
+	// URL used to authenticate and obtain a JWT.
+	LOGIN_URL_V4 = HOST_V4 + "/login"
+
+	// URL used to get basic series information by ID.
+	GET_SERIES_BY_ID_URL_V4 = HOST_V4 + "/series/%v"
+
+	// URL used to get a page of episodes for a series.
+	GET_EPISODES_URL_V4 = HOST_V4 + "/series/%v/episodes?page=%v"
+
+	// URL used for series searches.
+	SEARCH_SERIES_URL_V4 = HOST_V4 + "/search/series?name=%v"
+
+	// How long a JWT is considered valid before it is refreshed.
+	// TheTVDB tokens are valid for 24 hours; refresh well before that.
+	tokenTTL = 23 * time.Hour
+)
+
+// EpisodeV4 represents a TV show episode as returned by the JSON API.
+type EpisodeV4 struct {
+	Id            uint64   `json:"id"`
+	SeriesId      uint64   `json:"seriesId"`
+	Name          string   `json:"episodeName"`
+	AiredSeason   uint64   `json:"airedSeason"`
+	AiredEpisode  uint64   `json:"airedEpisodeNumber"`
+	FirstAired    string   `json:"firstAired"`
+	Overview      string   `json:"overview"`
+	Director      []string `json:"directors"`
+	Writers       []string `json:"writers"`
+	GuestStars    []string `json:"guestStars"`
+	Language      string   `json:"language"`
+	LastUpdated   uint64   `json:"lastUpdated"`
+	SiteRating    float64  `json:"siteRating"`
+	SiteRatingCnt uint64   `json:"siteRatingCount"`
+	ThumbnailPath string   `json:"filename"`
+}
+
+// SeriesV4 represents a TV show as returned by the JSON API.
+type SeriesV4 struct {
+	Id            uint64   `json:"id"`
+	SeriesName    string   `json:"seriesName"`
+	Aliases       []string `json:"aliases"`
+	Network       string   `json:"network"`
+	Status        string   `json:"status"`
+	FirstAired    string   `json:"firstAired"`
+	Overview      string   `json:"overview"`
+	ImdbId        string   `json:"imdbId"`
+	Genre         []string `json:"genre"`
+	Rating        string   `json:"rating"`
+	SiteRating    float64  `json:"siteRating"`
+	SiteRatingCnt uint64   `json:"siteRatingCount"`
+	Runtime       string   `json:"runtime"`
+	Banner        string   `json:"banner"`
+	LastUpdated   uint64   `json:"lastUpdated"`
+	AirsDayOfWeek string   `json:"airsDayOfWeek"`
+	AirsTime      string   `json:"airsTime"`
+	Seasons       map[uint64][]EpisodeV4
+}
+
+// SeriesListV4 represents a list of TV shows, typically search results.
+type SeriesListV4 struct {
+	Series []SeriesV4 `json:"data"`
+}
+
+// loginRequest is the body sent to POST /login.
+type loginRequest struct {
+	ApiKey   string `json:"apikey"`
+	UserKey  string `json:"userkey,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// loginResponse wraps the JWT returned by POST /login.
+type loginResponse struct {
+	Token string `json:"token"`
+}
+
+// episodesResponse wraps one page of /series/{id}/episodes.
+type episodesResponse struct {
+	Links struct {
+		Next uint64 `json:"next"`
+	} `json:"links"`
+	Data []EpisodeV4 `json:"data"`
+}
+
+// seriesResponse wraps a single /series/{id} result.
+type seriesResponse struct {
+	Data SeriesV4 `json:"data"`
+}
+
+// ClientV4Config configures a ClientV4.
+type ClientV4Config struct {
+	ApiKey   string
+	UserKey  string
+	Username string
+	Language string
+}
+
+// ClientV4 talks to the modern TheTVDB REST/JSON API, authenticating with a
+// JWT obtained from POST /login and refreshed as it expires.
+type ClientV4 struct {
+	config ClientV4Config
+
+	mu        sync.Mutex
+	token     string
+	tokenTime time.Time
+}
+
+// NewClientV4 creates a ClientV4 with the given configuration. The JWT is
+// obtained lazily on first use.
+func NewClientV4(config ClientV4Config) *ClientV4 {
+	if config.Language == "" {
+		config.Language = DEFAULT_LANGUAGE
+	}
+
+	return &ClientV4{config: config}
+}
+
+// login obtains a fresh JWT and caches it.
+func (client *ClientV4) login() (err error) {
+	body, err := json.Marshal(loginRequest{
+		ApiKey:   client.config.ApiKey,
+		UserKey:  client.config.UserKey,
+		Username: client.config.Username,
+	})
+
+	if err != nil {
+		return
+	}
+
+	response, err := http.Post(LOGIN_URL_V4, "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("tvdb: login failed with status %v", response.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(response.Body)
+
+	if err != nil {
+		return
+	}
+
+	login := loginResponse{}
+
+	if err = json.Unmarshal(data, &login); err != nil {
+		return
+	}
+
+	client.token = login.Token
+	client.tokenTime = time.Now()
+
+	return
+}
+
+// authToken returns a valid JWT, refreshing it first if it has expired.
+func (client *ClientV4) authToken() (token string, err error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.token == "" || time.Since(client.tokenTime) > tokenTTL {
+		if err = client.login(); err != nil {
+			return
+		}
+	}
+
+	return client.token, nil
+}
+
+// get performs an authenticated GET request against the JSON API.
+func (client *ClientV4) get(url string) (data []byte, err error) {
+	token, err := client.authToken()
+
+	if err != nil {
+		return
+	}
+
+	request, err := http.NewRequest("GET", url, nil)
+
+	if err != nil {
+		return
+	}
+
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("Accept-Language", client.config.Language)
+
+	response, err := http.DefaultClient.Do(request)
+
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("tvdb: request to %v failed with status %v", url, response.StatusCode)
+		return
+	}
+
+	return ioutil.ReadAll(response.Body)
+}
+
+// GetSeriesById gets a TV series by ID using the JSON API.
+func (client *ClientV4) GetSeriesById(id uint64) (series SeriesV4, err error) {
+	data, err := client.get(fmt.Sprintf(GET_SERIES_BY_ID_URL_V4, id))
+
+	if err != nil {
+		return
+	}
+
+	wrapper := seriesResponse{}
+
+	if err = json.Unmarshal(data, &wrapper); err != nil {
+		return
+	}
+
+	series = wrapper.Data
+
+	return
+}
+
+// SearchSeries searches for TV shows by name using the JSON API.
+func (client *ClientV4) SearchSeries(name string, maxResults int) (seriesList SeriesListV4, err error) {
+	data, err := client.get(fmt.Sprintf(SEARCH_SERIES_URL_V4, url.QueryEscape(name)))
+
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(data, &seriesList); err != nil {
+		return
+	}
+
+	if maxResults > 0 && len(seriesList.Series) > maxResults {
+		seriesList.Series = seriesList.Series[:maxResults]
+	}
+
+	return
+}
+
+// GetDetail fetches every episode page for a TV show and groups them by
+// season, mirroring the XML client's Series.GetDetail.
+func (client *ClientV4) GetDetail(series *SeriesV4) (err error) {
+	if series.Seasons == nil {
+		series.Seasons = make(map[uint64][]EpisodeV4)
+	}
+
+	page := uint64(0)
+
+	for {
+		data, err := client.get(fmt.Sprintf(GET_EPISODES_URL_V4, series.Id, page))
+
+		if err != nil {
+			return err
+		}
+
+		episodes := episodesResponse{}
+
+		if err = json.Unmarshal(data, &episodes); err != nil {
+			return err
+		}
+
+		for _, episode := range episodes.Data {
+			series.Seasons[episode.AiredSeason] = append(series.Seasons[episode.AiredSeason], episode)
+		}
+
+		if episodes.Links.Next == 0 {
+			break
+		}
+
+		page = episodes.Links.Next
+	}
+
+	return
+}
+
+// GetDetail fetches detail for every TV show in a list using the JSON API.
+func (seriesList *SeriesListV4) GetDetail(client *ClientV4) (err error) {
+	for seriesIndex := range seriesList.Series {
+		if err = client.GetDetail(&seriesList.Series[seriesIndex]); err != nil {
+			return
+		}
+	}
+
+	return
+}
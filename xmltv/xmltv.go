@@ -0,0 +1,195 @@
+// Package xmltv converts tvdb.Series, with populated Seasons, into the
+// XMLTV format consumed by EPG-aware media servers such as Kodi, Plex and
+// Jellyfin.
+package xmltv
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tvdb "github.com/yanfali/go-tvdb"
+)
+
+const (
+	header = `<?xml version="1.0" encoding="utf-8"?>` + "\n" +
+		`<!DOCTYPE tv SYSTEM "xmltv.dtd">` + "\n"
+
+	// dateTimeLayout is the XMLTV date/time format: YYYYMMDDHHMMSS followed
+	// by a space and the UTC offset, e.g. "20060102150400 -0700".
+	dateTimeLayout = "20060102150405 -0700"
+
+	// defaultRuntimeMinutes is used when Series.Runtime can't be parsed.
+	defaultRuntimeMinutes = 30
+)
+
+type tv struct {
+	XMLName    xml.Name    `xml:"tv"`
+	Generator  string      `xml:"generator-info-name,attr"`
+	Channels   []channel   `xml:"channel"`
+	Programmes []programme `xml:"programme"`
+}
+
+type channel struct {
+	Id           string        `xml:"id,attr"`
+	DisplayNames []displayName `xml:"display-name"`
+}
+
+type displayName struct {
+	Lang  string `xml:"lang,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type programme struct {
+	Start      string     `xml:"start,attr"`
+	Stop       string     `xml:"stop,attr"`
+	Channel    string     `xml:"channel,attr"`
+	Title      string     `xml:"title"`
+	SubTitle   string     `xml:"sub-title,omitempty"`
+	Desc       string     `xml:"desc,omitempty"`
+	EpisodeNum episodeNum `xml:"episode-num"`
+	Credits    *credits   `xml:"credits,omitempty"`
+	Categories []string   `xml:"category,omitempty"`
+}
+
+type episodeNum struct {
+	System string `xml:"system,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type credits struct {
+	Directors  []string `xml:"director,omitempty"`
+	Writers    []string `xml:"writer,omitempty"`
+	GuestStars []string `xml:"guest,omitempty"`
+}
+
+// Marshal writes series, with populated Seasons, to w as an XMLTV document.
+func Marshal(series []*tvdb.Series, w io.Writer) error {
+	doc := tv{Generator: "go-tvdb"}
+
+	for _, show := range series {
+		channelId := strconv.FormatUint(show.Id, 10)
+
+		doc.Channels = append(doc.Channels, channel{
+			Id:           channelId,
+			DisplayNames: []displayName{{Lang: show.Language, Value: show.SeriesName}},
+		})
+
+		doc.Programmes = append(doc.Programmes, programmesFor(show, channelId)...)
+	}
+
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	return encoder.Encode(doc)
+}
+
+// programmesFor builds one <programme> per episode across every season of
+// show, in season/episode order.
+func programmesFor(show *tvdb.Series, channelId string) (programmes []programme) {
+	seasons := make([]uint64, 0, len(show.Seasons))
+
+	for season := range show.Seasons {
+		seasons = append(seasons, season)
+	}
+
+	sort.Slice(seasons, func(i, j int) bool { return seasons[i] < seasons[j] })
+
+	for _, season := range seasons {
+		episodes := append([]tvdb.Episode(nil), show.Seasons[season]...)
+
+		sort.Slice(episodes, func(i, j int) bool { return episodes[i].EpisodeNumber < episodes[j].EpisodeNumber })
+
+		for _, episode := range episodes {
+			programmes = append(programmes, buildProgramme(show, channelId, episode))
+		}
+	}
+
+	return
+}
+
+func buildProgramme(show *tvdb.Series, channelId string, episode tvdb.Episode) programme {
+	start := airDateTime(episode.FirstAired, show.AirsTime)
+	stop := start.Add(runtimeDuration(show.Runtime))
+
+	return programme{
+		Start:   start.Format(dateTimeLayout),
+		Stop:    stop.Format(dateTimeLayout),
+		Channel: channelId,
+		Title:   episode.EpisodeName,
+		Desc:    episode.Overview,
+		EpisodeNum: episodeNum{
+			System: "xmltv_ns",
+			Value:  fmt.Sprintf("%d.%d.0/1", int64(episode.SeasonNumber)-1, int64(episode.EpisodeNumber)-1),
+		},
+		Credits:    buildCredits(episode),
+		Categories: show.Genre,
+	}
+}
+
+func buildCredits(episode tvdb.Episode) *credits {
+	guestStars := splitPipeList(episode.GuestStars)
+
+	if len(episode.Director) == 0 && len(episode.Writer) == 0 && len(guestStars) == 0 {
+		return nil
+	}
+
+	return &credits{
+		Directors:  []string(episode.Director),
+		Writers:    []string(episode.Writer),
+		GuestStars: guestStars,
+	}
+}
+
+// splitPipeList splits a raw pipe-delimited TheTVDB field, e.g. "|Foo|Bar|".
+func splitPipeList(value string) (list []string) {
+	for _, item := range strings.Split(strings.Trim(value, "|"), "|") {
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return
+}
+
+// airDateTime combines an episode's FirstAired date (YYYY-MM-DD) with its
+// series' AirsTime (e.g. "9:00 PM") into a single timestamp.
+func airDateTime(firstAired, airsTime string) time.Time {
+	date, err := time.Parse("2006-01-02", firstAired)
+
+	if err != nil {
+		return time.Time{}
+	}
+
+	if airsTime == "" {
+		return date
+	}
+
+	clock, err := time.Parse("3:04 PM", strings.ToUpper(strings.TrimSpace(airsTime)))
+
+	if err != nil {
+		return date
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, time.UTC)
+}
+
+// runtimeDuration parses Series.Runtime (minutes, as free text) into a
+// Duration, falling back to defaultRuntimeMinutes if it can't be parsed.
+func runtimeDuration(runtime string) time.Duration {
+	minutes, err := strconv.Atoi(strings.TrimSpace(runtime))
+
+	if err != nil || minutes <= 0 {
+		minutes = defaultRuntimeMinutes
+	}
+
+	return time.Duration(minutes) * time.Minute
+}